@@ -0,0 +1,192 @@
+package util
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// bundleManifestName is the well-known file inside a FlogoContribBundle
+// that lists every other file's digest.
+const bundleManifestName = "manifest.json"
+
+// BundleManifest maps a file path within the bundle to the hex-encoded
+// SHA-256 digest of its contents, checked when the bundle is opened so a tampered or
+// truncated bundle is rejected before any contribution source lands on
+// disk.
+type BundleManifest struct {
+	Digests map[string]string `json:"digests"`
+}
+
+// Bundle is a FlogoContribBundle: a tar+gzip archive holding one or more
+// contributions, each with its descriptor.json and Go sources, plus an
+// optional bundle.json (FlogoContribBundleDescriptor) and a manifest.json
+// of per-file digests. OCI image layout bundles aren't supported yet;
+// OpenBundle expects a tar+gzip archive.
+type Bundle struct {
+	path        string
+	manifest    *BundleManifest
+	descriptors []*FlogoContribDescriptor
+	files       map[string][]byte
+}
+
+// OpenBundle reads the bundle archive at path, verifies every file
+// against manifest.json (when present) and loads the descriptor.json of
+// each contribution it contains.
+func OpenBundle(path string) (*Bundle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' is not a gzipped tar bundle: %s", path, err.Error())
+	}
+	defer gz.Close()
+
+	b := &Bundle{path: path, files: make(map[string][]byte)}
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name, err := sanitizeBundlePath(hdr.Name)
+		if err != nil {
+			return nil, fmt.Errorf("bundle '%s': %s", path, err.Error())
+		}
+
+		content, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+
+		b.files[name] = content
+	}
+
+	if data, ok := b.files[bundleManifestName]; ok {
+		manifest := &BundleManifest{}
+		if err := json.Unmarshal(data, manifest); err != nil {
+			return nil, fmt.Errorf("failed to parse '%s' in bundle '%s': %s", bundleManifestName, path, err.Error())
+		}
+		b.manifest = manifest
+
+		if err := b.verify(); err != nil {
+			return nil, err
+		}
+	}
+
+	for name, content := range b.files {
+		if filepath.Base(name) != "descriptor.json" {
+			continue
+		}
+
+		desc := &FlogoContribDescriptor{}
+		if err := json.Unmarshal(content, desc); err != nil {
+			return nil, fmt.Errorf("failed to parse descriptor '%s' in bundle '%s': %s", name, path, err.Error())
+		}
+		b.descriptors = append(b.descriptors, desc)
+	}
+
+	return b, nil
+}
+
+// verify checks every digest recorded in the bundle's manifest against
+// the actual content extracted from the archive, and rejects any file in
+// the archive that the manifest does not list.
+func (b *Bundle) verify() error {
+	for name := range b.files {
+		if name == bundleManifestName {
+			continue
+		}
+		if _, ok := b.manifest.Digests[name]; !ok {
+			return fmt.Errorf("bundle '%s' contains file '%s' not listed in its manifest", b.path, name)
+		}
+	}
+
+	for name, wantDigest := range b.manifest.Digests {
+		content, ok := b.files[name]
+		if !ok {
+			return fmt.Errorf("bundle '%s' is missing file '%s' listed in its manifest", b.path, name)
+		}
+
+		sum := sha256.Sum256(content)
+		gotDigest := hex.EncodeToString(sum[:])
+
+		if gotDigest != wantDigest {
+			return fmt.Errorf("bundle '%s' failed integrity check: '%s' has digest %s, manifest expects %s", b.path, name, gotDigest, wantDigest)
+		}
+	}
+
+	return nil
+}
+
+// Descriptors returns the FlogoContribDescriptor of every contribution
+// packaged in the bundle.
+func (b *Bundle) Descriptors() []*FlogoContribDescriptor {
+	return b.descriptors
+}
+
+// ExtractTo writes every file in the bundle into gopath, preserving the
+// paths recorded in the archive (e.g. "src/github.com/foo/bar/activity.go").
+// The bundle manifest, if present, has already been verified in OpenBundle.
+func (b *Bundle) ExtractTo(gopath string) error {
+	for name, content := range b.files {
+		if name == bundleManifestName {
+			continue
+		}
+
+		// name was already sanitized by sanitizeBundlePath when the
+		// archive was read, but re-check here too: ExtractTo is the
+		// operation that actually touches the filesystem, and it
+		// shouldn't trust its caller to preserve that invariant.
+		name, err := sanitizeBundlePath(name)
+		if err != nil {
+			return fmt.Errorf("bundle '%s': %s", b.path, err.Error())
+		}
+
+		dest := filepath.Join(gopath, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(dest, content, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sanitizeBundlePath rejects tar entry names that would escape the
+// extraction root (CWE-22 "tar-slip"): absolute paths, and paths whose
+// cleaned form starts with "../". It returns the cleaned, slash-form
+// relative path to store/extract under.
+func sanitizeBundlePath(name string) (string, error) {
+	slashName := filepath.ToSlash(name)
+	cleaned := path.Clean(slashName)
+
+	if path.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("refusing to extract archive entry with unsafe path '%s'", name)
+	}
+
+	return cleaned, nil
+}
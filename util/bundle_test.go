@@ -0,0 +1,102 @@
+package util
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+type bundleFile struct {
+	name    string
+	content string
+}
+
+func writeTestBundle(t *testing.T, files []bundleFile) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	for _, f := range files {
+		hdr := &tar.Header{
+			Name: f.name,
+			Mode: 0644,
+			Size: int64(len(f.content)),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("write header for %s: %s", f.name, err)
+		}
+		if _, err := tw.Write([]byte(f.content)); err != nil {
+			t.Fatalf("write content for %s: %s", f.name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatalf("close tar writer: %s", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("close gzip writer: %s", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "bundle.tgz")
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("write bundle: %s", err)
+	}
+
+	return path
+}
+
+func TestOpenBundleExtractsDescriptorsAndFiles(t *testing.T) {
+	bundlePath := writeTestBundle(t, []bundleFile{
+		{"src/github.com/foo/activity/descriptor.json", `{"name":"foo","type":"flogo:activity","version":"1.0.0"}`},
+		{"src/github.com/foo/activity/activity.go", "package foo\n"},
+	})
+
+	b, err := OpenBundle(bundlePath)
+	if err != nil {
+		t.Fatalf("OpenBundle: %s", err)
+	}
+
+	descs := b.Descriptors()
+	if len(descs) != 1 || descs[0].Name != "foo" {
+		t.Fatalf("expected one descriptor named foo, got %v", descs)
+	}
+
+	dest := t.TempDir()
+	if err := b.ExtractTo(dest); err != nil {
+		t.Fatalf("ExtractTo: %s", err)
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(dest, "src", "github.com", "foo", "activity", "activity.go"))
+	if err != nil {
+		t.Fatalf("reading extracted file: %s", err)
+	}
+	if string(content) != "package foo\n" {
+		t.Fatalf("unexpected extracted content: %q", content)
+	}
+}
+
+func TestOpenBundleRejectsPathTraversal(t *testing.T) {
+	bundlePath := writeTestBundle(t, []bundleFile{
+		{"../../../../etc/cron.d/evil", "pwned\n"},
+	})
+
+	if _, err := OpenBundle(bundlePath); err == nil {
+		t.Fatalf("expected OpenBundle to reject a path-traversal entry, got nil error")
+	}
+}
+
+func TestBundleManifestMismatchRejected(t *testing.T) {
+	bundlePath := writeTestBundle(t, []bundleFile{
+		{"manifest.json", `{"digests":{"src/foo/activity.go":"0000000000000000000000000000000000000000000000000000000000000000"}}`},
+		{"src/foo/activity.go", "package foo\n"},
+	})
+
+	if _, err := OpenBundle(bundlePath); err == nil {
+		t.Fatalf("expected OpenBundle to reject a manifest digest mismatch, got nil error")
+	}
+}
@@ -0,0 +1,149 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/hcl"
+	"gopkg.in/yaml.v2"
+)
+
+// yamlKeyValue matches a "key: value" line, the shape a Flogo YAML
+// descriptor's top-level fields take. hclKeyValue matches the HCL
+// equivalent, "key = value". Content sniffing uses these to tell the two
+// apart when there's no file name to go by (e.g. descriptor content read
+// from a string or piped in).
+var (
+	yamlKeyValue = regexp.MustCompile(`(?m)^\s*[A-Za-z_][A-Za-z0-9_-]*\s*:\s*\S`)
+	hclKeyValue  = regexp.MustCompile(`(?m)^\s*[A-Za-z_][A-Za-z0-9_-]*\s*=\s*\S`)
+)
+
+// DescriptorFormat decodes/encodes a Flogo app or contribution descriptor
+// written in a particular serialization (JSON, YAML, HCL, ...), and can
+// detect whether a given file belongs to it.
+type DescriptorFormat interface {
+	// Name is the short identifier for the format, e.g. "json", "yaml", "hcl".
+	Name() string
+	// Detect reports whether fileName or content look like they belong to
+	// this format.
+	Detect(fileName string, content []byte) bool
+	// Decode unmarshals content into v.
+	Decode(content []byte, v interface{}) error
+	// Encode marshals v into this format.
+	Encode(v interface{}) ([]byte, error)
+}
+
+type jsonFormat struct{}
+
+func (f *jsonFormat) Name() string { return "json" }
+
+func (f *jsonFormat) Detect(fileName string, content []byte) bool {
+	if strings.HasSuffix(strings.ToLower(fileName), ".json") {
+		return true
+	}
+	trimmed := strings.TrimSpace(string(content))
+	return strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")
+}
+
+func (f *jsonFormat) Decode(content []byte, v interface{}) error {
+	return json.Unmarshal(content, v)
+}
+
+func (f *jsonFormat) Encode(v interface{}) ([]byte, error) {
+	return json.MarshalIndent(v, "", "  ")
+}
+
+type yamlFormat struct{}
+
+func (f *yamlFormat) Name() string { return "yaml" }
+
+func (f *yamlFormat) Detect(fileName string, content []byte) bool {
+	lc := strings.ToLower(fileName)
+	if strings.HasSuffix(lc, ".yaml") || strings.HasSuffix(lc, ".yml") {
+		return true
+	}
+
+	trimmed := strings.TrimSpace(string(content))
+	if trimmed == "" || strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		return false
+	}
+	return yamlKeyValue.MatchString(trimmed) && !hclKeyValue.MatchString(trimmed)
+}
+
+func (f *yamlFormat) Decode(content []byte, v interface{}) error {
+	return yaml.Unmarshal(content, v)
+}
+
+func (f *yamlFormat) Encode(v interface{}) ([]byte, error) {
+	return yaml.Marshal(v)
+}
+
+type hclFormat struct{}
+
+func (f *hclFormat) Name() string { return "hcl" }
+
+func (f *hclFormat) Detect(fileName string, content []byte) bool {
+	if strings.HasSuffix(strings.ToLower(fileName), ".hcl") {
+		return true
+	}
+
+	trimmed := strings.TrimSpace(string(content))
+	if trimmed == "" || strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		return false
+	}
+	return hclKeyValue.MatchString(trimmed)
+}
+
+func (f *hclFormat) Decode(content []byte, v interface{}) error {
+	return hcl.Unmarshal(content, v)
+}
+
+func (f *hclFormat) Encode(v interface{}) ([]byte, error) {
+	return nil, fmt.Errorf("encoding to hcl is not supported")
+}
+
+// descriptorFormats is the list of formats tried, in order, when a file
+// name alone isn't conclusive (e.g. piped input or an extensionless path).
+var descriptorFormats = []DescriptorFormat{&jsonFormat{}, &yamlFormat{}, &hclFormat{}}
+
+// DetectDescriptorFormat picks the DescriptorFormat for fileName/content,
+// preferring a match on file extension and falling back to content
+// sniffing. It defaults to JSON, the original descriptor format, when
+// nothing else matches.
+func DetectDescriptorFormat(fileName string, content []byte) DescriptorFormat {
+	if fileName != "" {
+		for _, f := range descriptorFormats {
+			if f.Detect(fileName, nil) {
+				return f
+			}
+		}
+	}
+	for _, f := range descriptorFormats {
+		if f.Detect("", content) {
+			return f
+		}
+	}
+	return &jsonFormat{}
+}
+
+// decodeDescriptor detects the format of fileName/content and decodes it
+// into v, validating against schema first so malformed descriptors fail
+// with a path pointing at the offending field rather than a generic
+// unmarshal error.
+func decodeDescriptor(fileName string, content []byte, schema *descriptorSchema, v interface{}) error {
+	format := DetectDescriptorFormat(fileName, content)
+
+	if schema != nil {
+		if errs := validateDescriptor(format, content, schema); len(errs) > 0 {
+			return fmt.Errorf("invalid %s descriptor '%s': %s", format.Name(), fileName, errs[0].Error())
+		}
+	}
+
+	if err := format.Decode(content, v); err != nil {
+		return fmt.Errorf("failed to parse %s descriptor '%s': %s", format.Name(), fileName, err.Error())
+	}
+
+	return nil
+}
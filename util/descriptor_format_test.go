@@ -0,0 +1,58 @@
+package util
+
+import "testing"
+
+func TestDetectDescriptorFormatByExtension(t *testing.T) {
+	cases := map[string]string{
+		"app.json": "json",
+		"app.yaml": "yaml",
+		"app.yml":  "yaml",
+		"app.hcl":  "hcl",
+	}
+
+	for fileName, want := range cases {
+		got := DetectDescriptorFormat(fileName, nil).Name()
+		if got != want {
+			t.Errorf("DetectDescriptorFormat(%q, nil) = %q, want %q", fileName, got, want)
+		}
+	}
+}
+
+func TestDetectDescriptorFormatByContent(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"json object", `{"name":"myapp","type":"flogo:app","version":"1.0.0"}`, "json"},
+		{"yaml", "name: myapp\ntype: flogo:app\nversion: 1.0.0\n", "yaml"},
+		{"hcl", "name = \"myapp\"\ntype = \"flogo:app\"\nversion = \"1.0.0\"\n", "hcl"},
+	}
+
+	for _, c := range cases {
+		got := DetectDescriptorFormat("", []byte(c.content)).Name()
+		if got != c.want {
+			t.Errorf("%s: DetectDescriptorFormat(\"\", ...) = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestParseAppDescriptorDetectsYAMLWithoutFileName(t *testing.T) {
+	d, err := ParseAppDescriptor("name: myapp\ntype: flogo:app\nversion: 1.0.0\n")
+	if err != nil {
+		t.Fatalf("ParseAppDescriptor returned error: %s", err)
+	}
+	if d.Name != "myapp" || d.Type != "flogo:app" || d.Version != "1.0.0" {
+		t.Fatalf("unexpected descriptor: %+v", d)
+	}
+}
+
+func TestParseAppDescriptorDetectsHCLWithoutFileName(t *testing.T) {
+	d, err := ParseAppDescriptor("name = \"myapp\"\ntype = \"flogo:app\"\nversion = \"1.0.0\"\n")
+	if err != nil {
+		t.Fatalf("ParseAppDescriptor returned error: %s", err)
+	}
+	if d.Name != "myapp" || d.Type != "flogo:app" || d.Version != "1.0.0" {
+		t.Fatalf("unexpected descriptor: %+v", d)
+	}
+}
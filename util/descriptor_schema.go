@@ -0,0 +1,63 @@
+package util
+
+import (
+	"fmt"
+)
+
+// descriptorSchema is a minimal structural check, not a JSON Schema
+// validator: just the set of fields a descriptor must have. It catches
+// truncated or copy-pasted descriptors early, reporting a "/field" path
+// rather than a line/column - full JSON Schema validation with source
+// position tracking is not implemented.
+type descriptorSchema struct {
+	required []string
+}
+
+// appDescriptorSchema validates FlogoAppDescriptor.
+var appDescriptorSchema = &descriptorSchema{
+	required: []string{"name", "type", "version"},
+}
+
+// contribDescriptorSchema validates FlogoContribDescriptor. It only
+// requires "type", matching what GetContribDescriptor has always checked
+// for: legacy trigger.json/action.json/activity.json files are commonly
+// missing "name" and "version" and still need to parse.
+var contribDescriptorSchema = &descriptorSchema{
+	required: []string{"type"},
+}
+
+// SchemaError describes a single schema validation failure, with a path
+// into the source document so editors/CI can point the user at it.
+type SchemaError struct {
+	Path    string
+	Message string
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// validateDescriptor decodes content as generic JSON/YAML/HCL (via format)
+// into a field map and checks schema.required are present and non-empty.
+func validateDescriptor(format DescriptorFormat, content []byte, schema *descriptorSchema) []*SchemaError {
+	var raw map[string]interface{}
+	if err := format.Decode(content, &raw); err != nil {
+		// Not structurally decodable at all - let the real Decode call
+		// below surface the underlying parse error.
+		return nil
+	}
+
+	var errs []*SchemaError
+	for _, field := range schema.required {
+		val, ok := raw[field]
+		if !ok {
+			errs = append(errs, &SchemaError{Path: "/" + field, Message: "required field is missing"})
+			continue
+		}
+		if s, isStr := val.(string); isStr && s == "" {
+			errs = append(errs, &SchemaError{Path: "/" + field, Message: "required field is empty"})
+		}
+	}
+
+	return errs
+}
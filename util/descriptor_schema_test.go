@@ -0,0 +1,33 @@
+package util
+
+import "testing"
+
+func TestValidateDescriptorReportsMissingRequiredFields(t *testing.T) {
+	format := &jsonFormat{}
+	content := []byte(`{"type":"flogo:app"}`)
+
+	errs := validateDescriptor(format, content, appDescriptorSchema)
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors (missing name and version), got %v", errs)
+	}
+}
+
+func TestValidateDescriptorPassesCompleteDescriptor(t *testing.T) {
+	format := &jsonFormat{}
+	content := []byte(`{"name":"myapp","type":"flogo:app","version":"1.0.0"}`)
+
+	errs := validateDescriptor(format, content, appDescriptorSchema)
+	if len(errs) != 0 {
+		t.Fatalf("expected no errors, got %v", errs)
+	}
+}
+
+func TestContribDescriptorSchemaOnlyRequiresType(t *testing.T) {
+	format := &jsonFormat{}
+	content := []byte(`{"type":"flogo:trigger","ref":"github.com/foo/trig"}`)
+
+	errs := validateDescriptor(format, content, contribDescriptorSchema)
+	if len(errs) != 0 {
+		t.Fatalf("expected no errors for a legacy-shaped descriptor with only type+ref, got %v", errs)
+	}
+}
@@ -2,7 +2,7 @@ package util
 
 import (
 	"bufio"
-	"encoding/json"
+	bytes2 "bytes"
 	"fmt"
 	"io/ioutil"
 	"log"
@@ -14,12 +14,12 @@ import (
 
 var exists = struct{}{}
 
-// ParseAppDescriptor parse the application descriptor
+// ParseAppDescriptor parse the application descriptor. The content is
+// sniffed and may be JSON, YAML or HCL.
 func ParseAppDescriptor(appJson string) (*FlogoAppDescriptor, error) {
 	descriptor := &FlogoAppDescriptor{}
 
-	err := json.Unmarshal([]byte(appJson), descriptor)
-
+	err := decodeDescriptor("", []byte(appJson), appDescriptorSchema, descriptor)
 	if err != nil {
 		return nil, err
 	}
@@ -84,6 +84,12 @@ func GetContribDescriptor(path string) (*FlogoContribDescriptor, error) {
 	files, err := ioutil.ReadDir(path)
 
 	if err != nil {
+		// Path lookup failed - the path may actually be a contribution
+		// name registered with the local registry rather than a directory.
+		if desc, regErr := NewLocalRegistry().Plugin(path); regErr == nil && desc != nil {
+			return desc, nil
+		}
+
 		fmt.Fprintf(os.Stderr, "Unable to find %v", path)
 		return nil, err
 	}
@@ -178,7 +184,13 @@ func getImports(appJsonPath string) ([]string, error) {
 
 	descriptor := &FlogoAppDescriptor{}
 
-	err = json.Unmarshal(bytes, descriptor)
+	// No schema validation here: getImports feeds GetImports, which falls
+	// back to getImportsLegacy whenever it comes back empty. A descriptor
+	// missing name/type/version but still fully parseable is exactly what
+	// that legacy path exists for, so only a genuine decode failure
+	// (content that isn't valid JSON/YAML/HCL at all) should error out
+	// here instead of falling through.
+	err = decodeDescriptor(appJsonPath, bytes, nil, descriptor)
 	if err != nil {
 		return nil, err
 	}
@@ -235,9 +247,9 @@ func ReadContribDescriptor(descriptorFile string) (*FlogoContribDescriptor, erro
 
 	descriptor := &FlogoContribDescriptor{}
 
-	err = json.Unmarshal(bytes, descriptor)
+	err = decodeDescriptor(descriptorFile, bytes, contribDescriptorSchema, descriptor)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse descriptor '%s': %s", descriptorFile, err.Error())
+		return nil, err
 	}
 
 	return descriptor, nil
@@ -256,9 +268,17 @@ func ParseImportPath(path string) (string, string) {
 	return path, ""
 }
 
+// appImports is a narrow decode target for just the "imports" field -
+// the only part of an AppConfig that GetImportsFromJSON needs in full.
+// Triggers/resources/actions are read via WalkRefs instead, so they're
+// never materialized into an []interface{} tree, however big they are.
+type appImports struct {
+	Imports []string `json:"imports,omitempty"`
+}
+
 func GetImportsFromJSON(path string) (Imports, error) {
 
-	appConfig := &AppConfig{}
+	imports := &appImports{}
 	//fmt.Println("Path is", path)
 	descriptorJson, err := os.Open(path)
 	if err != nil {
@@ -270,19 +290,27 @@ func GetImportsFromJSON(path string) (Imports, error) {
 		return nil, err
 	}
 
-	err = json.Unmarshal(bytes, appConfig)
+	err = decodeDescriptor(path, bytes, nil, imports)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Unable to marshal ")
 		return nil, err
 	}
 
-	refs := getRefsFromConfig(appConfig)
+	var refs []string
+	err = WalkRefs(bytes2.NewReader(bytes), func(ref string, refPath []string) error {
+		refs = append(refs, ref)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	var result Imports
 
 	for _, key := range refs {
 		found := false
 
-		for _, contrib := range appConfig.Imports {
+		for _, contrib := range imports.Imports {
 			flogoImport, err := ParseImport(contrib)
 			if err != nil {
 				return nil, err
@@ -295,6 +323,17 @@ func GetImportsFromJSON(path string) (Imports, error) {
 		}
 		//
 		if !found {
+			// Not declared as a full Go import path in "imports" - see if
+			// it's a contribution registered with the local registry.
+			if desc, regErr := NewLocalRegistry().Plugin(key); regErr == nil && desc != nil && desc.Ref != "" {
+				flogoImport, err := ParseImport(desc.Ref)
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, flogoImport)
+				continue
+			}
+
 			flogoImport, err := ParseImport(key)
 			if err != nil {
 				return nil, err
@@ -306,39 +345,3 @@ func GetImportsFromJSON(path string) (Imports, error) {
 	return result, nil
 }
 
-func getRefsFromConfig(appConfig *AppConfig) []string {
-	var results []string
-
-	results = append(results, extractDependencies(appConfig.Triggers)...)
-
-	results = append(results, extractDependencies(appConfig.Resources)...)
-
-	results = append(results, extractDependencies(appConfig.Actions)...)
-
-	return results
-}
-
-func extractDependencies(resource interface{}) []string {
-	var refs []string
-	switch resource.(type) {
-	case map[string]interface{}:
-
-		for key, val := range resource.(map[string]interface{}) {
-			//Type is deprecated use ref instead.
-			if key == "ref" {
-				val = strings.Trim(val.(string), "#")
-				refs = append(refs, val.(string))
-				return refs
-			}
-			refs = append(refs, extractDependencies(resource.(map[string]interface{})[key])...)
-		}
-	case []interface{}:
-
-		for i := 0; i < len(resource.([]interface{})); i++ {
-			refs = append(refs, extractDependencies(resource.([]interface{})[i])...)
-		}
-	default:
-		return append(refs)
-	}
-	return refs
-}
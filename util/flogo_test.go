@@ -0,0 +1,26 @@
+package util
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetContribDescriptorAcceptsLegacyDescriptorMissingVersion(t *testing.T) {
+	dir := t.TempDir()
+	legacy := `{"type":"flogo:trigger","ref":"github.com/foo/trig"}`
+	if err := ioutil.WriteFile(filepath.Join(dir, "trigger.json"), []byte(legacy), 0644); err != nil {
+		t.Fatalf("write trigger.json: %s", err)
+	}
+
+	desc, err := GetContribDescriptor(dir)
+	if err != nil {
+		t.Fatalf("GetContribDescriptor returned error: %s", err)
+	}
+	if desc == nil {
+		t.Fatal("expected a descriptor for a legacy trigger.json missing version, got nil")
+	}
+	if desc.Type != "flogo:trigger" || !desc.IsLegacy {
+		t.Fatalf("unexpected descriptor: %+v", desc)
+	}
+}
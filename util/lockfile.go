@@ -0,0 +1,152 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// lockfileVersion is bumped whenever the hashing scheme below changes in a
+// way that invalidates previously written lockfiles.
+const lockfileVersion = 1
+
+// LockedImport records everything needed to verify that a resolved import
+// hasn't changed since it was fetched.
+type LockedImport struct {
+	GoImportPath string `json:"goImportPath"`
+	Version      string `json:"version,omitempty"`
+	Hash         string `json:"hash"`
+}
+
+// Lockfile is the content of a flogo.lock file: one LockedImport per
+// import resolved by GetImports/GetImportsFromJSON.
+type Lockfile struct {
+	Version int            `json:"version"`
+	Imports []LockedImport `json:"imports"`
+}
+
+// Mismatch describes an import whose recorded hash no longer matches what
+// was fetched into gopath.
+type Mismatch struct {
+	GoImportPath string
+	Expected     string
+	Actual       string
+}
+
+// WriteLockfile hashes each import's fetched source tree and writes the
+// result to path as JSON.
+func WriteLockfile(path string, imports Imports, gopath string) error {
+	lock := &Lockfile{Version: lockfileVersion}
+
+	for _, imp := range imports {
+		hash, err := HashContribTree(filepath.Join(gopath, "src", imp.GoImportPath()))
+		if err != nil {
+			return fmt.Errorf("unable to hash '%s': %s", imp.GoImportPath(), err.Error())
+		}
+
+		lock.Imports = append(lock.Imports, LockedImport{
+			GoImportPath: imp.GoImportPath(),
+			Version:      imp.Version(),
+			Hash:         hash,
+		})
+	}
+
+	sort.Slice(lock.Imports, func(i, j int) bool {
+		return lock.Imports[i].GoImportPath < lock.Imports[j].GoImportPath
+	})
+
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// VerifyLockfile reads the lockfile at path and re-hashes each import's
+// source tree under gopath, returning a Mismatch for every import whose
+// hash has changed. An import present in the lockfile but missing from
+// gopath is reported as a Mismatch with an empty Actual hash.
+func VerifyLockfile(path string, gopath string) ([]Mismatch, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lock := &Lockfile{}
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("failed to parse lockfile '%s': %s", path, err.Error())
+	}
+
+	var mismatches []Mismatch
+
+	for _, imp := range lock.Imports {
+		actual, err := HashContribTree(filepath.Join(gopath, "src", imp.GoImportPath))
+		if err != nil {
+			mismatches = append(mismatches, Mismatch{GoImportPath: imp.GoImportPath, Expected: imp.Hash})
+			continue
+		}
+
+		if actual != imp.Hash {
+			mismatches = append(mismatches, Mismatch{GoImportPath: imp.GoImportPath, Expected: imp.Hash, Actual: actual})
+		}
+	}
+
+	return mismatches, nil
+}
+
+// HashContribTree computes a tarsum-style content hash of a contribution's
+// source tree: every non-directory entry under root is hashed as
+// "name|mode|size|sha256(content)", the resulting entry strings are
+// sorted for determinism, and the sorted list is hashed again to produce
+// the final digest.
+func HashContribTree(root string) (string, error) {
+	var entries []string
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		h := sha256.New()
+		if _, err := io.Copy(h, f); err != nil {
+			return err
+		}
+
+		entries = append(entries, fmt.Sprintf("%s|%o|%d|%s", filepath.ToSlash(rel), info.Mode(), info.Size(), hex.EncodeToString(h.Sum(nil))))
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sort.Strings(entries)
+
+	final := sha256.New()
+	for _, entry := range entries {
+		io.WriteString(final, entry)
+		io.WriteString(final, "\n")
+	}
+
+	return "sha256:" + hex.EncodeToString(final.Sum(nil)), nil
+}
@@ -0,0 +1,123 @@
+package util
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeContribTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("mkdir %s: %s", path, err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("write %s: %s", path, err)
+		}
+	}
+}
+
+func TestHashContribTreeDeterministic(t *testing.T) {
+	root := t.TempDir()
+	writeContribTree(t, root, map[string]string{
+		"descriptor.json": `{"name":"foo","type":"flogo:activity","version":"1.0.0"}`,
+		"activity.go":     "package foo\n",
+	})
+
+	first, err := HashContribTree(root)
+	if err != nil {
+		t.Fatalf("HashContribTree: %s", err)
+	}
+
+	second, err := HashContribTree(root)
+	if err != nil {
+		t.Fatalf("HashContribTree: %s", err)
+	}
+
+	if first != second {
+		t.Fatalf("hash of unchanged tree differs: %s != %s", first, second)
+	}
+}
+
+func TestHashContribTreeChangesWithContent(t *testing.T) {
+	root := t.TempDir()
+	writeContribTree(t, root, map[string]string{
+		"descriptor.json": `{"name":"foo","type":"flogo:activity","version":"1.0.0"}`,
+		"activity.go":     "package foo\n",
+	})
+
+	before, err := HashContribTree(root)
+	if err != nil {
+		t.Fatalf("HashContribTree: %s", err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(root, "activity.go"), []byte("package foo\n\nfunc Eval() {}\n"), 0644); err != nil {
+		t.Fatalf("rewrite file: %s", err)
+	}
+
+	after, err := HashContribTree(root)
+	if err != nil {
+		t.Fatalf("HashContribTree: %s", err)
+	}
+
+	if before == after {
+		t.Fatalf("hash did not change after editing a tracked file")
+	}
+}
+
+func TestWriteLockfileThenVerifyLockfileRoundTrip(t *testing.T) {
+	gopath := t.TempDir()
+	contribDir := filepath.Join(gopath, "src", "github.com", "foo", "activity")
+	writeContribTree(t, contribDir, map[string]string{
+		"descriptor.json": `{"name":"foo","type":"flogo:activity","version":"1.0.0"}`,
+		"activity.go":     "package foo\n",
+	})
+
+	imp, err := ParseImport("github.com/foo/activity")
+	if err != nil {
+		t.Fatalf("ParseImport: %s", err)
+	}
+	imports := Imports{imp}
+
+	lockPath := filepath.Join(gopath, "flogo.lock")
+	if err := WriteLockfile(lockPath, imports, gopath); err != nil {
+		t.Fatalf("WriteLockfile: %s", err)
+	}
+
+	written, err := ioutil.ReadFile(lockPath)
+	if err != nil {
+		t.Fatalf("reading written lockfile: %s", err)
+	}
+	lock := &Lockfile{}
+	if err := json.Unmarshal(written, lock); err != nil {
+		t.Fatalf("parsing written lockfile: %s", err)
+	}
+	if len(lock.Imports) != 1 || lock.Imports[0].GoImportPath != "github.com/foo/activity" || lock.Imports[0].Hash == "" {
+		t.Fatalf("unexpected lockfile content: %+v", lock)
+	}
+
+	mismatches, err := VerifyLockfile(lockPath, gopath)
+	if err != nil {
+		t.Fatalf("VerifyLockfile: %s", err)
+	}
+	if len(mismatches) != 0 {
+		t.Fatalf("expected no mismatches right after WriteLockfile, got %v", mismatches)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(contribDir, "activity.go"), []byte("package foo\n\n// tampered\n"), 0644); err != nil {
+		t.Fatalf("tamper with file: %s", err)
+	}
+
+	mismatches, err = VerifyLockfile(lockPath, gopath)
+	if err != nil {
+		t.Fatalf("VerifyLockfile: %s", err)
+	}
+	if len(mismatches) != 1 || mismatches[0].GoImportPath != "github.com/foo/activity" {
+		t.Fatalf("expected a mismatch for the tampered import, got %v", mismatches)
+	}
+}
@@ -0,0 +1,125 @@
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ContribRegistry discovers contributions that are available locally,
+// without needing a full Go import path - analogous to Docker's local
+// plugin registry.
+type ContribRegistry interface {
+	// Plugins returns every contribution the registry can find.
+	Plugins() ([]*FlogoContribDescriptor, error)
+	// Plugin looks up a single contribution by name.
+	Plugin(name string) (*FlogoContribDescriptor, error)
+}
+
+// contribSearchPathsOverride lets tests point NewLocalRegistry at a fixed
+// set of directories instead of the real, environment-dependent ones.
+var contribSearchPathsOverride []string
+
+// contribSearchPaths are the well-known directories scanned for installed
+// contributions, in order. $FLOGO_CONTRIB_PATH, when set, is searched
+// first so it can override the system-wide locations.
+func contribSearchPaths() []string {
+	if contribSearchPathsOverride != nil {
+		return contribSearchPathsOverride
+	}
+
+	var paths []string
+
+	if p := os.Getenv("FLOGO_CONTRIB_PATH"); p != "" {
+		paths = append(paths, filepath.SplitList(p)...)
+	}
+
+	if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(home, ".flogo", "contribs"))
+	}
+
+	paths = append(paths, filepath.Join(string(filepath.Separator), "etc", "flogo", "contribs"))
+
+	return paths
+}
+
+// LocalRegistry is a ContribRegistry backed by the local filesystem. It
+// scans contribSearchPaths for either a "<name>.json" spec file or a
+// directory containing a "descriptor.json" bundle.
+type LocalRegistry struct {
+	SearchPaths []string
+}
+
+// NewLocalRegistry creates a LocalRegistry that scans the standard
+// search paths (~/.flogo/contribs, /etc/flogo/contribs, $FLOGO_CONTRIB_PATH).
+func NewLocalRegistry() *LocalRegistry {
+	return &LocalRegistry{SearchPaths: contribSearchPaths()}
+}
+
+// Plugins returns every contribution discoverable across the registry's
+// search paths. Paths are scanned in order and a name found in an earlier
+// path shadows the same name found later.
+func (r *LocalRegistry) Plugins() ([]*FlogoContribDescriptor, error) {
+	seen := make(map[string]struct{})
+	var plugins []*FlogoContribDescriptor
+
+	for _, dir := range r.SearchPaths {
+		entries, err := ioutil.ReadDir(dir)
+		if err != nil {
+			// A missing search path is normal (not every location exists
+			// on every machine), so skip it rather than failing.
+			continue
+		}
+
+		for _, entry := range entries {
+			name := entry.Name()
+
+			if entry.IsDir() {
+				desc, err := ReadContribDescriptor(filepath.Join(dir, name, "descriptor.json"))
+				if err != nil {
+					continue
+				}
+				if _, ok := seen[desc.Name]; ok {
+					continue
+				}
+				seen[desc.Name] = exists
+				plugins = append(plugins, desc)
+				continue
+			}
+
+			if !strings.HasSuffix(strings.ToLower(name), ".json") {
+				continue
+			}
+
+			desc, err := ReadContribDescriptor(filepath.Join(dir, name))
+			if err != nil || desc.Type == "" {
+				continue
+			}
+			if _, ok := seen[desc.Name]; ok {
+				continue
+			}
+			seen[desc.Name] = exists
+			plugins = append(plugins, desc)
+		}
+	}
+
+	return plugins, nil
+}
+
+// Plugin looks up a single contribution by name across the registry's
+// search paths, returning nil if it isn't found.
+func (r *LocalRegistry) Plugin(name string) (*FlogoContribDescriptor, error) {
+	plugins, err := r.Plugins()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range plugins {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+
+	return nil, nil
+}
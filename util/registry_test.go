@@ -0,0 +1,108 @@
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("mkdir for %s: %s", path, err)
+	}
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("write %s: %s", path, err)
+	}
+}
+
+func TestLocalRegistryDiscoversSpecFilesAndDescriptorDirs(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFile(t, filepath.Join(dir, "myactivity.json"), `{"name":"myactivity","type":"flogo:activity"}`)
+	writeFile(t, filepath.Join(dir, "mytrigger", "descriptor.json"), `{"name":"mytrigger","type":"flogo:trigger"}`)
+	// Not a .json file and not a descriptor dir - should be ignored.
+	writeFile(t, filepath.Join(dir, "README.md"), "not a contribution")
+
+	reg := &LocalRegistry{SearchPaths: []string{dir}}
+
+	plugins, err := reg.Plugins()
+	if err != nil {
+		t.Fatalf("Plugins: %s", err)
+	}
+
+	names := map[string]bool{}
+	for _, p := range plugins {
+		names[p.Name] = true
+	}
+	if !names["myactivity"] || !names["mytrigger"] {
+		t.Fatalf("expected myactivity and mytrigger, got %v", plugins)
+	}
+	if len(plugins) != 2 {
+		t.Fatalf("expected exactly 2 plugins, got %d: %v", len(plugins), plugins)
+	}
+}
+
+func TestLocalRegistryEarlierSearchPathShadowsLater(t *testing.T) {
+	first := t.TempDir()
+	second := t.TempDir()
+
+	writeFile(t, filepath.Join(first, "shared.json"), `{"name":"shared","type":"flogo:activity","version":"2.0.0"}`)
+	writeFile(t, filepath.Join(second, "shared.json"), `{"name":"shared","type":"flogo:activity","version":"1.0.0"}`)
+
+	reg := &LocalRegistry{SearchPaths: []string{first, second}}
+
+	desc, err := reg.Plugin("shared")
+	if err != nil {
+		t.Fatalf("Plugin: %s", err)
+	}
+	if desc == nil {
+		t.Fatal("expected a plugin named shared, got nil")
+	}
+	if desc.Version != "2.0.0" {
+		t.Fatalf("expected the first search path's version to win, got %q", desc.Version)
+	}
+}
+
+func TestLocalRegistryPluginNotFound(t *testing.T) {
+	reg := &LocalRegistry{SearchPaths: []string{t.TempDir()}}
+
+	desc, err := reg.Plugin("does-not-exist")
+	if err != nil {
+		t.Fatalf("Plugin: %s", err)
+	}
+	if desc != nil {
+		t.Fatalf("expected nil for an unknown plugin, got %v", desc)
+	}
+}
+
+func TestLocalRegistrySkipsMissingSearchPaths(t *testing.T) {
+	reg := &LocalRegistry{SearchPaths: []string{filepath.Join(t.TempDir(), "does-not-exist")}}
+
+	plugins, err := reg.Plugins()
+	if err != nil {
+		t.Fatalf("Plugins: %s", err)
+	}
+	if len(plugins) != 0 {
+		t.Fatalf("expected no plugins from a missing search path, got %v", plugins)
+	}
+}
+
+func TestGetContribDescriptorFallsBackToRegistry(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "myactivity.json"), `{"name":"myactivity","type":"flogo:activity"}`)
+
+	origPaths := contribSearchPathsOverride
+	contribSearchPathsOverride = []string{dir}
+	defer func() { contribSearchPathsOverride = origPaths }()
+
+	desc, err := GetContribDescriptor("myactivity")
+	if err != nil {
+		t.Fatalf("GetContribDescriptor: %s", err)
+	}
+	if desc == nil || desc.Name != "myactivity" {
+		t.Fatalf("expected GetContribDescriptor to resolve 'myactivity' via the registry, got %v", desc)
+	}
+}
@@ -0,0 +1,151 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// refFrame tracks our position within one level of JSON nesting while
+// streaming: which key we're under (object) or which index we're at
+// (array), and whether an object frame is expecting a key or a value next.
+//
+// For object frames, refVal/typVal buffer the "ref" and "type" values
+// seen directly on this object (if any) so the fallback can be resolved
+// once the whole object has been read, rather than as each key arrives.
+type refFrame struct {
+	isArray     bool
+	index       int
+	key         string
+	awaitingKey bool
+
+	refVal  *string
+	refPath []string
+	typVal  *string
+	typPath []string
+}
+
+// WalkRefs streams the JSON document read from r, invoking fn once per
+// object with the object's "ref" value, or its "type" value when the
+// object has no "ref" of its own, along with the JSON pointer path to
+// whichever value was used.
+//
+// Unlike the map[string]interface{} recursion it replaces, WalkRefs holds
+// only the current frame stack in memory rather than the whole decoded
+// tree, so it stays roughly O(1) in descriptor size - the old approach
+// measurably slowed down on flow descriptors with 100k+ activities. It
+// also doesn't stop at the first "ref" found inside a map: the old
+// extractDependencies returned as soon as it saw one, silently dropping
+// every sibling ref found later in the same object tree.
+func WalkRefs(r io.Reader, fn func(ref string, path []string) error) error {
+	dec := json.NewDecoder(r)
+
+	var stack []*refFrame
+	var path []string
+
+	closeFrame := func() error {
+		closing := stack[len(stack)-1]
+
+		if !closing.isArray {
+			switch {
+			case closing.refVal != nil:
+				if err := fn(*closing.refVal, closing.refPath); err != nil {
+					return err
+				}
+			case closing.typVal != nil:
+				if err := fn(*closing.typVal, closing.typPath); err != nil {
+					return err
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		path = path[:len(path)-1]
+		if len(stack) > 0 {
+			parent := stack[len(stack)-1]
+			if parent.isArray {
+				parent.index++
+			} else {
+				parent.awaitingKey = true
+			}
+		}
+		return nil
+	}
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to walk refs at %s: %s", pointer(path), err.Error())
+		}
+
+		if delim, ok := tok.(json.Delim); ok {
+			if (delim == '{' || delim == '[') && len(stack) > 0 && stack[len(stack)-1].isArray {
+				// A nested container is an element of the enclosing array:
+				// point the path at its index before descending into it.
+				path[len(path)-1] = strconv.Itoa(stack[len(stack)-1].index)
+			}
+			switch delim {
+			case '{':
+				stack = append(stack, &refFrame{awaitingKey: true})
+				path = append(path, "")
+			case '[':
+				stack = append(stack, &refFrame{isArray: true})
+				path = append(path, "0")
+			case '}', ']':
+				if err := closeFrame(); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if len(stack) == 0 {
+			// Top-level scalar document; nothing to track.
+			continue
+		}
+
+		top := stack[len(stack)-1]
+
+		if !top.isArray && top.awaitingKey {
+			top.key = tok.(string)
+			path[len(path)-1] = top.key
+			top.awaitingKey = false
+			continue
+		}
+
+		if top.isArray {
+			path[len(path)-1] = strconv.Itoa(top.index)
+		}
+
+		if s, ok := tok.(string); ok && !top.isArray {
+			switch top.key {
+			case "ref":
+				v := strings.Trim(s, "#")
+				top.refVal = &v
+				top.refPath = append([]string(nil), path...)
+			case "type":
+				v := s
+				top.typVal = &v
+				top.typPath = append([]string(nil), path...)
+			}
+		}
+
+		if top.isArray {
+			top.index++
+		} else {
+			top.awaitingKey = true
+		}
+	}
+}
+
+func pointer(path []string) string {
+	if len(path) == 0 {
+		return "/"
+	}
+	return "/" + strings.Join(path, "/")
+}
@@ -0,0 +1,69 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func collectRefs(t *testing.T, doc string) []string {
+	t.Helper()
+
+	var refs []string
+	err := WalkRefs(strings.NewReader(doc), func(ref string, path []string) error {
+		refs = append(refs, ref)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WalkRefs returned error: %s", err)
+	}
+
+	return refs
+}
+
+func TestWalkRefsPrefersRefOverType(t *testing.T) {
+	doc := `{"triggers":[{"id":"t1","ref":"github.com/foo/trig","type":"flogo:trigger"}]}`
+
+	got := collectRefs(t, doc)
+	want := []string{"github.com/foo/trig"}
+
+	if len(got) != len(want) || got[0] != want[0] {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestWalkRefsFallsBackToType(t *testing.T) {
+	doc := `{"actions":[{"id":"a1","type":"flogo:action"}]}`
+
+	got := collectRefs(t, doc)
+	want := []string{"flogo:action"}
+
+	if len(got) != len(want) || got[0] != want[0] {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestWalkRefsCollectsNestedSiblings(t *testing.T) {
+	doc := `{
+		"triggers":[{"id":"t1","ref":"github.com/foo/trig"}],
+		"actions":[
+			{"id":"a1","ref":"github.com/foo/flow"},
+			{"id":"a2","settings":{"ref":"#github.com/foo/nested"}}
+		]
+	}`
+
+	got := collectRefs(t, doc)
+	want := map[string]bool{
+		"github.com/foo/trig":   true,
+		"github.com/foo/flow":   true,
+		"github.com/foo/nested": true,
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want keys of %v", got, want)
+	}
+	for _, ref := range got {
+		if !want[ref] {
+			t.Fatalf("unexpected ref %q in %v", ref, got)
+		}
+	}
+}